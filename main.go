@@ -1,98 +1,41 @@
 package main
 
 import (
-	"bufio"
+	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"log/slog"
-	"net"
 	"os"
-	"strings"
-)
+	"time"
 
-const (
-	defaultWhoisServer = "whois.iana.org"
-	defaultWhoisPort   = 43
-	maxBufferSize      = 32 * 1024 // Max buffer size for WHOIS response
+	"github.com/DAcodedBEAT/whois-go/pkg/rdap"
+	"github.com/DAcodedBEAT/whois-go/pkg/whois"
 )
 
-// Options holds command-line options and defaults for the WHOIS client.
-type Options struct {
+// CLIOptions holds command-line options for the whois CLI.
+type CLIOptions struct {
 	ShowRedirects bool
+	JSON          bool
 	Server        string
 	Port          int
+	CacheTTL      time.Duration
+	Mode          whois.Mode
+	Concurrency   int
 }
 
-// WHOISResult represents the result of a WHOIS query, containing the response string and any associated error.
-type WHOISResult struct {
+// Result pairs a WHOIS response with any error encountered looking it up.
+type Result struct {
 	Response string
+	Info     *whois.Info
 	Error    error
 }
 
-// recursiveWhoIsQuery follows WHOIS queries recursively to obtain the complete responses for each domain.
-func recursiveWhoIsQuery(opts Options, domains []string) map[string]WHOISResult {
-	responseMap := make(map[string]WHOISResult)
-
-	for _, domain := range domains {
-		result := performSingleWhoIsQuery(opts.Server, opts.Port, domain)
-		responseMap[domain] = result
-	}
-
-	return responseMap
-}
-
-// performSingleWhoIsQuery performs a single WHOIS query for a given domain.
-func performSingleWhoIsQuery(server string, port int, domain string) WHOISResult {
-	conn, err := net.Dial("tcp", fmt.Sprintf("%s:%d", server, port))
-	if err != nil {
-		return WHOISResult{"", fmt.Errorf("failed to connect to WHOIS server: %v", err)}
-	}
-	defer func(conn net.Conn) {
-		connCloseErr := conn.Close()
-		if connCloseErr != nil {
-			slog.Error("could not close connection", "error", connCloseErr)
-		}
-	}(conn)
-
-	_, err = fmt.Fprintf(conn, "%s\r\n", domain)
-	if err != nil {
-		return WHOISResult{"", fmt.Errorf("failed to send WHOIS query: %v", err)}
-	}
-
-	reader := bufio.NewReader(conn)
-	var sb strings.Builder
-
-	for {
-		line, err := reader.ReadString('\n') // Read until newline
-		if err != nil {
-			break // EOF or read error
-		}
-
-		sb.WriteString(line)
-
-		// Check for redirection
-		trimmed := strings.TrimSpace(strings.ToLower(line))
-		if strings.HasPrefix(trimmed, "whois server:") {
-			redirect := strings.TrimSpace(line[len("whois server:"):])
-			if redirect != "" && redirect != server {
-				return performSingleWhoIsQuery(redirect, port, domain) // Recursive call for redirection
-			}
-		}
-
-		if sb.Len() >= maxBufferSize {
-			break
-		}
-	}
-
-	return WHOISResult{sb.String(), nil}
-}
-
 func main() {
 	opts := parseOptions()
 
 	domains := flag.Args()
 
-	// Setup slog with default handler
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	slog.SetDefault(logger)
 
@@ -101,41 +44,123 @@ func main() {
 		os.Exit(1)
 	}
 
-	result := recursiveWhoIsQuery(opts, domains)
+	clientOpts := []whois.Option{
+		whois.WithServer(opts.Server),
+		whois.WithPort(opts.Port),
+		whois.WithShowRedirects(opts.ShowRedirects),
+	}
+	if opts.Server != whois.DefaultServer {
+		// An explicit -h server is queried as given, not auto-routed.
+		clientOpts = append(clientOpts, whois.WithAutoRoute(false))
+	}
+	if opts.CacheTTL > 0 {
+		negativeTTL := opts.CacheTTL / 5
+		clientOpts = append(clientOpts, whois.WithCache(
+			whois.NewMemoryCache(whois.DefaultCacheMaxEntries, opts.CacheTTL, negativeTTL),
+		))
+	}
+	if opts.Mode != whois.ModeWHOIS {
+		clientOpts = append(clientOpts, whois.WithMode(opts.Mode), whois.WithRDAP(rdap.NewClient()))
+	}
+	if opts.Concurrency > 0 {
+		clientOpts = append(clientOpts, whois.WithConcurrency(opts.Concurrency))
+	}
+	client := whois.NewClient(clientOpts...)
 
-	printResults(result)
+	results := lookupAll(client, domains, opts.JSON)
+
+	printResults(results, opts.JSON)
 }
 
-// parseOptions parses command-line options and returns Options struct.
-func parseOptions() Options {
-	opts := Options{
-		Server: defaultWhoisServer,
-		Port:   defaultWhoisPort,
+// lookupAll queries every domain, fanned out across client's
+// configured concurrency. When withInfo is set, each response is also
+// parsed into a whois.Info.
+func lookupAll(client *whois.Client, domains []string, withInfo bool) map[string]Result {
+	batch := client.QueryBatch(context.Background(), domains)
+
+	results := make(map[string]Result, len(batch))
+	for domain, outcome := range batch {
+		if outcome.Error != nil {
+			results[domain] = Result{Error: outcome.Error}
+			continue
+		}
+
+		result := Result{Response: outcome.Response.Raw, Info: outcome.Response.Info}
+		if withInfo && result.Info == nil {
+			result.Info = whois.Parse(outcome.Response.Raw)
+		}
+		results[domain] = result
+	}
+
+	return results
+}
+
+// parseOptions parses command-line options and returns CLIOptions.
+func parseOptions() CLIOptions {
+	opts := CLIOptions{
+		Server: whois.DefaultServer,
+		Port:   whois.DefaultPort,
 	}
 
 	showRedirects := flag.Bool("i", false, "Show redirect results too")
+	jsonOutput := flag.Bool("json", false, "Print parsed results as JSON instead of raw WHOIS text")
 	server := flag.String("h", opts.Server, "Server to query")
 	port := flag.Int("p", opts.Port, "Port number to query")
+	cacheTTL := flag.Duration("cache-ttl", 0, "Cache successful lookups for this long (0 disables caching)")
+	mode := flag.String("mode", "whois", "Query protocol to use: whois, rdap, or auto (RDAP with WHOIS fallback)")
+	concurrency := flag.Int("concurrency", 0, "Number of domains to query in parallel (0 uses the library default)")
 
 	flag.Parse()
 
 	opts.ShowRedirects = *showRedirects
+	opts.JSON = *jsonOutput
 	opts.Server = *server
 	opts.Port = *port
+	opts.CacheTTL = *cacheTTL
+	opts.Mode = parseMode(*mode)
+	opts.Concurrency = *concurrency
 
 	return opts
 }
 
-// printResults prints WHOIS query results.
-func printResults(results map[string]WHOISResult) {
+// parseMode maps the -mode flag's value to a whois.Mode, defaulting
+// to whois.ModeWHOIS for an unrecognized value.
+func parseMode(mode string) whois.Mode {
+	switch mode {
+	case "rdap":
+		return whois.ModeRDAP
+	case "auto":
+		return whois.ModeAuto
+	default:
+		return whois.ModeWHOIS
+	}
+}
+
+// printResults prints WHOIS query results, as raw text or, if asJSON
+// is set, as the parsed whois.Info for each domain.
+func printResults(results map[string]Result, asJSON bool) {
 	erroredWhoIsLookupCount := 0
-	for domain, whoisResp := range results {
-		if err := whoisResp.Error; err != nil {
+	for domain, result := range results {
+		if err := result.Error; err != nil {
 			slog.Error("Error querying WHOIS", "domain", domain, "error", err)
 			erroredWhoIsLookupCount++
 		}
 
-		fmt.Printf("WHOIS response for %s:\n%s\n\n", domain, whoisResp.Response)
+		if asJSON {
+			encoded, err := json.MarshalIndent(result.Info, "", "  ")
+			if err != nil {
+				slog.Error("Error encoding WHOIS info", "domain", domain, "error", err)
+				continue
+			}
+			fmt.Printf("%s\n", encoded)
+			continue
+		}
+
+		response := result.Response
+		if response == "" && result.Info != nil {
+			response = fmt.Sprintf("%+v", result.Info)
+		}
+		fmt.Printf("WHOIS response for %s:\n%s\n\n", domain, response)
 	}
 
 	if erroredWhoIsLookupCount == len(results) {
@@ -0,0 +1,224 @@
+package rdap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// bootstrapFile is the shape of the IANA RDAP bootstrap registries
+// (dns.json, ipv4.json, ipv6.json, asn.json): a list of services,
+// each pairing a set of keys (TLDs, CIDR blocks, or ASN ranges) with
+// the RDAP base URLs authoritative for them.
+type bootstrapFile struct {
+	Services [][]json.RawMessage `json:"services"`
+}
+
+// bootstrapCache memoizes fetched/parsed bootstrap files, keyed by
+// their full URL, since they change rarely relative to how often a
+// long-running process might query them.
+var bootstrapCache sync.Map // map[string]*bootstrapFile
+
+func (c *Client) fetchBootstrap(ctx context.Context, name string) (*bootstrapFile, error) {
+	url := strings.TrimRight(c.BootstrapBaseURL, "/") + "/" + name
+
+	if cached, ok := bootstrapCache.Load(url); ok {
+		return cached.(*bootstrapFile), nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("rdap: failed to build bootstrap request for %s: %w", url, err)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("rdap: failed to fetch bootstrap file %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("rdap: bootstrap file %s returned status %d", url, resp.StatusCode)
+	}
+
+	var file bootstrapFile
+	if err := json.NewDecoder(resp.Body).Decode(&file); err != nil {
+		return nil, fmt.Errorf("rdap: failed to decode bootstrap file %s: %w", url, err)
+	}
+
+	bootstrapCache.Store(url, &file)
+	return &file, nil
+}
+
+// serverFor resolves the RDAP base URL and query kind ("domain",
+// "ip", or "autnum") for query, consulting the matching IANA
+// bootstrap registry.
+func (c *Client) serverFor(ctx context.Context, query string) (base, kind string, err error) {
+	query = strings.TrimSpace(query)
+
+	if ip := net.ParseIP(query); ip != nil {
+		file := "ipv4.json"
+		if ip.To4() == nil {
+			file = "ipv6.json"
+		}
+		base, err = c.bootstrapIPServer(ctx, file, ip)
+		return base, "ip", err
+	}
+
+	if asn, ok := parseASN(query); ok {
+		base, err = c.bootstrapASNServer(ctx, asn)
+		return base, "autnum", err
+	}
+
+	base, err = c.bootstrapDomainServer(ctx, query)
+	return base, "domain", err
+}
+
+func (c *Client) bootstrapDomainServer(ctx context.Context, query string) (string, error) {
+	file, err := c.fetchBootstrap(ctx, "dns.json")
+	if err != nil {
+		return "", err
+	}
+
+	tld := tldOf(query)
+
+	for _, svc := range file.Services {
+		keys, urls, ok := decodeService(svc)
+		if !ok {
+			continue
+		}
+		for _, key := range keys {
+			if strings.EqualFold(key, tld) {
+				if url, ok := firstURL(urls); ok {
+					return url, nil
+				}
+			}
+		}
+	}
+
+	return "", fmt.Errorf("rdap: no bootstrap entry for TLD %q", tld)
+}
+
+func (c *Client) bootstrapIPServer(ctx context.Context, file string, ip net.IP) (string, error) {
+	bootstrap, err := c.fetchBootstrap(ctx, file)
+	if err != nil {
+		return "", err
+	}
+
+	var bestURL string
+	var bestOnes = -1
+
+	for _, svc := range bootstrap.Services {
+		keys, urls, ok := decodeService(svc)
+		if !ok {
+			continue
+		}
+		for _, key := range keys {
+			_, network, err := net.ParseCIDR(key)
+			if err != nil || !network.Contains(ip) {
+				continue
+			}
+			ones, _ := network.Mask.Size()
+			if ones > bestOnes {
+				if url, ok := firstURL(urls); ok {
+					bestOnes = ones
+					bestURL = url
+				}
+			}
+		}
+	}
+
+	if bestURL == "" {
+		return "", fmt.Errorf("rdap: no bootstrap entry covers %s", ip)
+	}
+
+	return bestURL, nil
+}
+
+func (c *Client) bootstrapASNServer(ctx context.Context, asn uint64) (string, error) {
+	file, err := c.fetchBootstrap(ctx, "asn.json")
+	if err != nil {
+		return "", err
+	}
+
+	for _, svc := range file.Services {
+		keys, urls, ok := decodeService(svc)
+		if !ok {
+			continue
+		}
+		for _, key := range keys {
+			start, end, ok := parseASNRange(key)
+			if !ok || asn < start || asn > end {
+				continue
+			}
+			if url, ok := firstURL(urls); ok {
+				return url, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("rdap: no bootstrap entry covers AS%d", asn)
+}
+
+// decodeService unpacks one [keys, urls] entry of a bootstrap file's
+// "services" array.
+func decodeService(svc []json.RawMessage) (keys, urls []string, ok bool) {
+	if len(svc) != 2 {
+		return nil, nil, false
+	}
+	if err := json.Unmarshal(svc[0], &keys); err != nil {
+		return nil, nil, false
+	}
+	if err := json.Unmarshal(svc[1], &urls); err != nil {
+		return nil, nil, false
+	}
+	return keys, urls, true
+}
+
+func firstURL(urls []string) (string, bool) {
+	if len(urls) == 0 {
+		return "", false
+	}
+	return urls[0], true
+}
+
+// tldOf returns the lowercase TLD (the label after the last dot) of a
+// domain query, with any trailing dot stripped first.
+func tldOf(query string) string {
+	query = strings.TrimSuffix(query, ".")
+	idx := strings.LastIndex(query, ".")
+	if idx < 0 {
+		return strings.ToLower(query)
+	}
+	return strings.ToLower(query[idx+1:])
+}
+
+// parseASN reports whether query names an ASN, either as "AS<n>" or a
+// bare number, returning its numeric value.
+func parseASN(query string) (uint64, bool) {
+	trimmed := strings.TrimPrefix(strings.ToUpper(query), "AS")
+	n, err := strconv.ParseUint(trimmed, 10, 32)
+	if err != nil || trimmed == "" {
+		return 0, false
+	}
+	return n, true
+}
+
+// parseASNRange parses a bootstrap ASN key of the form "<start>-<end>".
+func parseASNRange(key string) (start, end uint64, ok bool) {
+	parts := strings.SplitN(key, "-", 2)
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+	start, err1 := strconv.ParseUint(parts[0], 10, 32)
+	end, err2 := strconv.ParseUint(parts[1], 10, 32)
+	if err1 != nil || err2 != nil {
+		return 0, 0, false
+	}
+	return start, end, true
+}
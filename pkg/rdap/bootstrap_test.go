@@ -0,0 +1,163 @@
+package rdap
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+)
+
+func TestTldOf(t *testing.T) {
+	tests := map[string]string{
+		"example.com":   "com",
+		"example.co.uk": "uk",
+		"example.com.":  "com",
+		"com":           "com",
+	}
+
+	for query, want := range tests {
+		if got := tldOf(query); got != want {
+			t.Errorf("tldOf(%q) = %q, want %q", query, got, want)
+		}
+	}
+}
+
+func TestParseASN(t *testing.T) {
+	tests := []struct {
+		query  string
+		want   uint64
+		wantOK bool
+	}{
+		{"AS15169", 15169, true},
+		{"as15169", 15169, true},
+		{"As15169", 15169, true},
+		{"aS15169", 15169, true},
+		{"15169", 15169, true},
+		{"example.com", 0, false},
+		{"", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseASN(tt.query)
+		if ok != tt.wantOK || got != tt.want {
+			t.Errorf("parseASN(%q) = (%d, %v), want (%d, %v)", tt.query, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestParseASNRange(t *testing.T) {
+	start, end, ok := parseASNRange("15169-15169")
+	if !ok || start != 15169 || end != 15169 {
+		t.Errorf("parseASNRange(15169-15169) = (%d, %d, %v), want (15169, 15169, true)", start, end, ok)
+	}
+
+	if _, _, ok := parseASNRange("not-a-range"); ok {
+		t.Error("parseASNRange(not-a-range) ok = true, want false")
+	}
+}
+
+func TestDecodeService(t *testing.T) {
+	var svc []json.RawMessage
+	if err := json.Unmarshal([]byte(`[["com","net"],["https://rdap.verisign.com/"]]`), &svc); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	keys, urls, ok := decodeService(svc)
+	if !ok {
+		t.Fatal("decodeService() ok = false, want true")
+	}
+	if len(keys) != 2 || keys[0] != "com" || keys[1] != "net" {
+		t.Errorf("keys = %v, want [com net]", keys)
+	}
+	if len(urls) != 1 || urls[0] != "https://rdap.verisign.com/" {
+		t.Errorf("urls = %v, want [https://rdap.verisign.com/]", urls)
+	}
+
+	if _, _, ok := decodeService(svc[:1]); ok {
+		t.Error("decodeService() with one element ok = true, want false")
+	}
+}
+
+// bootstrapFileFromJSON decodes raw into a bootstrapFile, as
+// fetchBootstrap would after a successful HTTP round trip.
+func bootstrapFileFromJSON(t *testing.T, raw string) *bootstrapFile {
+	t.Helper()
+	var file bootstrapFile
+	if err := json.Unmarshal([]byte(raw), &file); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	return &file
+}
+
+func TestBootstrapIPServerPicksLongestPrefix(t *testing.T) {
+	c := &Client{BootstrapBaseURL: "https://rdap-test.invalid/longest-prefix"}
+	bootstrapCache.Store(c.BootstrapBaseURL+"/ipv4.json", bootstrapFileFromJSON(t, `{
+		"services": [
+			[["192.0.0.0/8"], ["https://rdap.broad.example/"]],
+			[["192.0.2.0/24"], ["https://rdap.narrow.example/"]]
+		]
+	}`))
+
+	url, err := c.bootstrapIPServer(context.Background(), "ipv4.json", net.ParseIP("192.0.2.1"))
+	if err != nil {
+		t.Fatalf("bootstrapIPServer() error = %v", err)
+	}
+	if url != "https://rdap.narrow.example/" {
+		t.Errorf("url = %q, want the most specific /24 match", url)
+	}
+}
+
+func TestBootstrapIPServerNoMatch(t *testing.T) {
+	c := &Client{BootstrapBaseURL: "https://rdap-test.invalid/no-match"}
+	bootstrapCache.Store(c.BootstrapBaseURL+"/ipv4.json", bootstrapFileFromJSON(t, `{
+		"services": [
+			[["198.51.100.0/24"], ["https://rdap.example/"]]
+		]
+	}`))
+
+	if _, err := c.bootstrapIPServer(context.Background(), "ipv4.json", net.ParseIP("192.0.2.1")); err == nil {
+		t.Error("bootstrapIPServer() error = nil, want no-match error")
+	}
+}
+
+func TestBootstrapASNServer(t *testing.T) {
+	c := &Client{BootstrapBaseURL: "https://rdap-test.invalid/asn"}
+	bootstrapCache.Store(c.BootstrapBaseURL+"/asn.json", bootstrapFileFromJSON(t, `{
+		"services": [
+			[["15100-15200"], ["https://rdap.asn.example/"]]
+		]
+	}`))
+
+	url, err := c.bootstrapASNServer(context.Background(), 15169)
+	if err != nil {
+		t.Fatalf("bootstrapASNServer() error = %v", err)
+	}
+	if url != "https://rdap.asn.example/" {
+		t.Errorf("url = %q, want https://rdap.asn.example/", url)
+	}
+
+	if _, err := c.bootstrapASNServer(context.Background(), 9999); err == nil {
+		t.Error("bootstrapASNServer(9999) error = nil, want no-match error")
+	}
+}
+
+func TestBootstrapDomainServer(t *testing.T) {
+	c := &Client{BootstrapBaseURL: "https://rdap-test.invalid/domain"}
+	bootstrapCache.Store(c.BootstrapBaseURL+"/dns.json", bootstrapFileFromJSON(t, `{
+		"services": [
+			[["com", "net"], ["https://rdap.verisign.com/"]]
+		]
+	}`))
+
+	url, err := c.bootstrapDomainServer(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("bootstrapDomainServer() error = %v", err)
+	}
+	if url != "https://rdap.verisign.com/" {
+		t.Errorf("url = %q, want https://rdap.verisign.com/", url)
+	}
+
+	if _, err := c.bootstrapDomainServer(context.Background(), "example.org"); err == nil {
+		t.Error("bootstrapDomainServer(example.org) error = nil, want no-match error")
+	}
+}
@@ -0,0 +1,69 @@
+// Package rdap implements a minimal client for the Registration Data
+// Access Protocol (RDAP), the HTTPS/JSON successor to port-43 WHOIS
+// that gTLD registries are progressively migrating to.
+package rdap
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/DAcodedBEAT/whois-go/pkg/whois"
+)
+
+// DefaultBootstrapBaseURL is the IANA service that publishes which
+// RDAP server is authoritative for a given TLD, IP range, or ASN.
+const DefaultBootstrapBaseURL = "https://data.iana.org/rdap"
+
+// ErrNotFound is returned by Query when the RDAP server has no record
+// for the query, i.e. it responded 404.
+var ErrNotFound = errors.New("rdap: no record found")
+
+// Client is an RDAP client.
+type Client struct {
+	HTTPClient       *http.Client
+	BootstrapBaseURL string
+}
+
+// Option configures a Client constructed by NewClient.
+type Option func(*Client)
+
+// WithHTTPClient overrides the *http.Client used for both bootstrap
+// and RDAP requests.
+func WithHTTPClient(h *http.Client) Option {
+	return func(c *Client) { c.HTTPClient = h }
+}
+
+// WithBootstrapBaseURL overrides the IANA bootstrap service used to
+// resolve which RDAP server is authoritative for a query.
+func WithBootstrapBaseURL(url string) Option {
+	return func(c *Client) { c.BootstrapBaseURL = url }
+}
+
+// NewClient builds a Client with sensible defaults, applying opts in
+// order.
+func NewClient(opts ...Option) *Client {
+	c := &Client{
+		HTTPClient:       http.DefaultClient,
+		BootstrapBaseURL: DefaultBootstrapBaseURL,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Query looks up a domain, IPv4/IPv6 address, or ASN ("AS<n>" or a
+// bare number) over RDAP and parses the response into the same
+// whois.Info struct the WHOIS parser produces. It returns ErrNotFound
+// if the authoritative server has no record for query.
+func (c *Client) Query(ctx context.Context, query string) (*whois.Info, error) {
+	base, kind, err := c.serverFor(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.queryServer(ctx, base, kind, query)
+}
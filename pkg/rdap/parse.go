@@ -0,0 +1,179 @@
+package rdap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/DAcodedBEAT/whois-go/pkg/whois"
+)
+
+// rdapObject is the subset of the RDAP response object model (RFC
+// 9083) this package understands, covering domain, IP network, and
+// autnum lookups.
+type rdapObject struct {
+	Handle  string   `json:"handle"`
+	LdhName string   `json:"ldhName"`
+	Name    string   `json:"name"`
+	Country string   `json:"country"`
+	Status  []string `json:"status"`
+	Events  []struct {
+		Action string `json:"eventAction"`
+		Date   string `json:"eventDate"`
+	} `json:"events"`
+	Nameservers []struct {
+		LdhName string `json:"ldhName"`
+	} `json:"nameservers"`
+	Entities []struct {
+		Roles      []string           `json:"roles"`
+		VCardArray [2]json.RawMessage `json:"vcardArray"`
+	} `json:"entities"`
+	Cidr0Cidrs []struct {
+		V4Prefix string `json:"v4prefix"`
+		V6Prefix string `json:"v6prefix"`
+		Length   int    `json:"length"`
+	} `json:"cidr0_cidrs"`
+	StartAddress string `json:"startAddress"`
+	EndAddress   string `json:"endAddress"`
+	StartAutnum  *int64 `json:"startAutnum"`
+	EndAutnum    *int64 `json:"endAutnum"`
+}
+
+// queryServer performs the RDAP HTTP lookup for query ("domain",
+// "ip", or "autnum") against base and parses the result.
+func (c *Client) queryServer(ctx context.Context, base, kind, query string) (*whois.Info, error) {
+	url := strings.TrimRight(base, "/") + "/" + rdapPath(kind, query)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("rdap: failed to build request for %s: %w", url, err)
+	}
+	req.Header.Set("Accept", "application/rdap+json")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("rdap: request to %s failed: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("rdap: %s returned status %d", url, resp.StatusCode)
+	}
+
+	var obj rdapObject
+	if err := json.NewDecoder(resp.Body).Decode(&obj); err != nil {
+		return nil, fmt.Errorf("rdap: failed to decode response from %s: %w", url, err)
+	}
+
+	return obj.toInfo(), nil
+}
+
+// rdapPath builds the RDAP request path for the given query kind, per
+// RFC 9082.
+func rdapPath(kind, query string) string {
+	switch kind {
+	case "ip":
+		return "ip/" + query
+	case "autnum":
+		asn, _ := parseASN(query)
+		return "autnum/" + strconv.FormatUint(asn, 10)
+	default:
+		return "domain/" + query
+	}
+}
+
+// toInfo converts an RDAP response object into the same whois.Info
+// struct the plaintext WHOIS parser produces.
+func (o *rdapObject) toInfo() *whois.Info {
+	info := &whois.Info{Fields: make(map[string][]string)}
+
+	info.Domain = o.LdhName
+	info.Status = o.Status
+	info.NetName = o.Name
+	info.OrgName = o.Name
+	info.Country = o.Country
+	info.ASN = o.Handle
+
+	for _, ns := range o.Nameservers {
+		info.NameServers = append(info.NameServers, ns.LdhName)
+	}
+
+	for _, event := range o.Events {
+		switch event.Action {
+		case "registration":
+			info.CreatedDate = event.Date
+		case "last changed", "last update of RDAP database":
+			info.UpdatedDate = event.Date
+		case "expiration":
+			info.ExpiresDate = event.Date
+		}
+	}
+
+	for _, entity := range o.Entities {
+		if !hasRole(entity.Roles, "registrar") {
+			continue
+		}
+		if name := vcardFullName(entity.VCardArray); name != "" {
+			info.Registrar = name
+		}
+	}
+
+	for _, cidr := range o.Cidr0Cidrs {
+		switch {
+		case cidr.V4Prefix != "":
+			info.CIDR = fmt.Sprintf("%s/%d", cidr.V4Prefix, cidr.Length)
+		case cidr.V6Prefix != "":
+			info.CIDR = fmt.Sprintf("%s/%d", cidr.V6Prefix, cidr.Length)
+		}
+	}
+	if info.CIDR == "" && o.StartAddress != "" && o.EndAddress != "" {
+		info.CIDR = o.StartAddress + " - " + o.EndAddress
+	}
+
+	if o.StartAutnum != nil {
+		info.ASN = strconv.FormatInt(*o.StartAutnum, 10)
+	}
+
+	return info
+}
+
+func hasRole(roles []string, role string) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// vcardFullName extracts the "fn" (formatted name) property from a
+// jCard (RFC 7095) vcardArray, as used by RDAP entities.
+func vcardFullName(vcardArray [2]json.RawMessage) string {
+	var props [][]json.RawMessage
+	if err := json.Unmarshal(vcardArray[1], &props); err != nil {
+		return ""
+	}
+
+	for _, prop := range props {
+		if len(prop) < 4 {
+			continue
+		}
+		var name string
+		if err := json.Unmarshal(prop[0], &name); err != nil || name != "fn" {
+			continue
+		}
+		var value string
+		if err := json.Unmarshal(prop[3], &value); err != nil {
+			continue
+		}
+		return value
+	}
+
+	return ""
+}
@@ -0,0 +1,155 @@
+package rdap
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func decodeRDAPObject(t *testing.T, raw string) *rdapObject {
+	t.Helper()
+	var obj rdapObject
+	if err := json.Unmarshal([]byte(raw), &obj); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	return &obj
+}
+
+func TestToInfoDomain(t *testing.T) {
+	obj := decodeRDAPObject(t, `{
+		"ldhName": "EXAMPLE.COM",
+		"status": ["active"],
+		"nameservers": [{"ldhName": "A.IANA-SERVERS.NET"}, {"ldhName": "B.IANA-SERVERS.NET"}],
+		"events": [
+			{"eventAction": "registration", "eventDate": "1995-08-14T04:00:00Z"},
+			{"eventAction": "last changed", "eventDate": "2024-08-14T07:01:35Z"},
+			{"eventAction": "expiration", "eventDate": "2025-08-13T04:00:00Z"}
+		],
+		"entities": [
+			{
+				"roles": ["registrar"],
+				"vcardArray": ["vcard", [
+					["version", {}, "text", "4.0"],
+					["fn", {}, "text", "Example Registrar, Inc."]
+				]]
+			},
+			{
+				"roles": ["technical"],
+				"vcardArray": ["vcard", [
+					["fn", {}, "text", "Should Be Ignored"]
+				]]
+			}
+		]
+	}`)
+
+	info := obj.toInfo()
+
+	if info.Domain != "EXAMPLE.COM" {
+		t.Errorf("Domain = %q, want EXAMPLE.COM", info.Domain)
+	}
+	if len(info.Status) != 1 || info.Status[0] != "active" {
+		t.Errorf("Status = %v, want [active]", info.Status)
+	}
+	if len(info.NameServers) != 2 {
+		t.Errorf("NameServers = %v, want 2 entries", info.NameServers)
+	}
+	if info.CreatedDate != "1995-08-14T04:00:00Z" {
+		t.Errorf("CreatedDate = %q", info.CreatedDate)
+	}
+	if info.UpdatedDate != "2024-08-14T07:01:35Z" {
+		t.Errorf("UpdatedDate = %q", info.UpdatedDate)
+	}
+	if info.ExpiresDate != "2025-08-13T04:00:00Z" {
+		t.Errorf("ExpiresDate = %q", info.ExpiresDate)
+	}
+	if info.Registrar != "Example Registrar, Inc." {
+		t.Errorf("Registrar = %q, want the registrar entity's fn, not the technical contact's", info.Registrar)
+	}
+}
+
+func TestToInfoCidr0(t *testing.T) {
+	obj := decodeRDAPObject(t, `{
+		"handle": "NET-192-0-2-0-1",
+		"cidr0_cidrs": [{"v4prefix": "192.0.2.0", "length": 24}]
+	}`)
+
+	info := obj.toInfo()
+	if info.CIDR != "192.0.2.0/24" {
+		t.Errorf("CIDR = %q, want 192.0.2.0/24", info.CIDR)
+	}
+}
+
+func TestToInfoStartEndAddressFallback(t *testing.T) {
+	obj := decodeRDAPObject(t, `{
+		"startAddress": "192.0.2.0",
+		"endAddress": "192.0.2.255"
+	}`)
+
+	info := obj.toInfo()
+	if info.CIDR != "192.0.2.0 - 192.0.2.255" {
+		t.Errorf("CIDR = %q, want the start-end range", info.CIDR)
+	}
+}
+
+func TestToInfoAutnum(t *testing.T) {
+	startAutnum := int64(15169)
+	obj := &rdapObject{Handle: "AS15169", StartAutnum: &startAutnum}
+
+	info := obj.toInfo()
+	if info.ASN != "15169" {
+		t.Errorf("ASN = %q, want 15169 (from startAutnum, not handle)", info.ASN)
+	}
+}
+
+func TestVCardFullName(t *testing.T) {
+	var vcardArray [2]json.RawMessage
+	if err := json.Unmarshal([]byte(`["vcard", [
+		["version", {}, "text", "4.0"],
+		["fn", {}, "text", "Jane Doe"]
+	]]`), &vcardArray); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	if got := vcardFullName(vcardArray); got != "Jane Doe" {
+		t.Errorf("vcardFullName() = %q, want Jane Doe", got)
+	}
+}
+
+func TestVCardFullNameMissing(t *testing.T) {
+	var vcardArray [2]json.RawMessage
+	if err := json.Unmarshal([]byte(`["vcard", [
+		["version", {}, "text", "4.0"]
+	]]`), &vcardArray); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	if got := vcardFullName(vcardArray); got != "" {
+		t.Errorf("vcardFullName() = %q, want empty string", got)
+	}
+}
+
+func TestHasRole(t *testing.T) {
+	if !hasRole([]string{"administrative", "registrar"}, "registrar") {
+		t.Error("hasRole() = false, want true")
+	}
+	if hasRole([]string{"administrative"}, "registrar") {
+		t.Error("hasRole() = true, want false")
+	}
+}
+
+func TestRdapPath(t *testing.T) {
+	tests := []struct {
+		kind  string
+		query string
+		want  string
+	}{
+		{"ip", "192.0.2.1", "ip/192.0.2.1"},
+		{"autnum", "AS15169", "autnum/15169"},
+		{"domain", "example.com", "domain/example.com"},
+	}
+
+	for _, tt := range tests {
+		if got := rdapPath(tt.kind, tt.query); got != tt.want {
+			t.Errorf("rdapPath(%q, %q) = %q, want %q", tt.kind, tt.query, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package whois
+
+import (
+	"context"
+	"sync"
+)
+
+// BatchResult pairs one QueryBatch query with its outcome.
+type BatchResult struct {
+	Response *Response
+	Error    error
+}
+
+// QueryBatch looks up every query in queries, running up to
+// c.Concurrency of them at once. Each destination server is still
+// subject to c.RateLimiter, so raising Concurrency fans a batch out
+// across servers without overwhelming any single one of them.
+func (c *Client) QueryBatch(ctx context.Context, queries []string) map[string]BatchResult {
+	concurrency := c.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	jobs := make(chan string)
+	results := make(map[string]BatchResult, len(queries))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for query := range jobs {
+				resp, err := c.Query(ctx, query)
+
+				mu.Lock()
+				results[query] = BatchResult{Response: resp, Error: err}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for _, query := range queries {
+			select {
+			case jobs <- query:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,130 @@
+package whois
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// CymruBulkServer is the well-known Team Cymru server that implements
+// the bulk WHOIS protocol BulkQuery speaks.
+const CymruBulkServer = "whois.cymru.com"
+
+// BulkRecord is one row of a Cymru-style bulk WHOIS response, as
+// returned by servers such as whois.cymru.com for IP and ASN lookups.
+type BulkRecord struct {
+	ASN       string
+	IP        string
+	BGPPrefix string
+	CC        string
+	Registry  string
+	Allocated string
+	ASName    string
+}
+
+// BulkQuery looks up items (IP addresses or "AS<n>" ASNs) against
+// server in a single TCP connection, using the Team Cymru bulk WHOIS
+// protocol: a "begin" line, an optional "verbose" line, one line per
+// item, then an "end" line. This lets callers resolve thousands of
+// items without the per-item connection overhead Query pays.
+func (c *Client) BulkQuery(ctx context.Context, server string, items []string) ([]BulkRecord, error) {
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	addr := net.JoinHostPort(server, strconv.Itoa(c.Port))
+	conn, err := c.Dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to WHOIS server %s: %w", server, err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return nil, fmt.Errorf("failed to set connection deadline: %w", err)
+		}
+	}
+
+	if _, err := fmt.Fprint(conn, "begin\n"); err != nil {
+		return nil, fmt.Errorf("failed to send bulk query: %w", err)
+	}
+	if c.Verbose {
+		if _, err := fmt.Fprint(conn, "verbose\n"); err != nil {
+			return nil, fmt.Errorf("failed to send bulk query: %w", err)
+		}
+	}
+	for _, item := range items {
+		if _, err := fmt.Fprintf(conn, "%s\n", item); err != nil {
+			return nil, fmt.Errorf("failed to send bulk query: %w", err)
+		}
+	}
+	if _, err := fmt.Fprint(conn, "end\n"); err != nil {
+		return nil, fmt.Errorf("failed to send bulk query: %w", err)
+	}
+
+	var records []BulkRecord
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		record, ok := parseBulkLine(scanner.Text())
+		if !ok {
+			continue // header/banner line, not a data row
+		}
+		records = append(records, record)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("bulk WHOIS query to %s: %w", server, err)
+	}
+
+	return records, nil
+}
+
+// parseBulkLine parses a single pipe-delimited Cymru bulk response
+// row. The default (non-verbose) shape is "ASN | IP | AS Name"; with
+// "verbose" sent, it's "ASN | IP | BGP Prefix | CC | Registry |
+// Allocated | AS Name". Both shapes' header row has the same field
+// count as their data rows, so headers are filtered by content
+// instead: Cymru always puts a number (or "NA") in the first field of
+// a data row, but the literal "AS" in the header.
+func parseBulkLine(line string) (BulkRecord, bool) {
+	fields := strings.Split(line, "|")
+	for i := range fields {
+		fields[i] = strings.TrimSpace(fields[i])
+	}
+
+	if len(fields) != 3 && len(fields) != 7 {
+		return BulkRecord{}, false
+	}
+	if !isASNField(fields[0]) {
+		return BulkRecord{}, false
+	}
+
+	record := BulkRecord{ASN: fields[0], IP: fields[1]}
+	if len(fields) == 3 {
+		record.ASName = fields[2]
+		return record, true
+	}
+
+	record.BGPPrefix = fields[2]
+	record.CC = fields[3]
+	record.Registry = fields[4]
+	record.Allocated = fields[5]
+	record.ASName = fields[6]
+	return record, true
+}
+
+// isASNField reports whether s is a value Cymru would put in a data
+// row's ASN column: a number, or "NA" when an ASN couldn't be
+// determined. The header row instead contains the literal "AS".
+func isASNField(s string) bool {
+	if strings.EqualFold(s, "NA") {
+		return true
+	}
+	_, err := strconv.ParseUint(s, 10, 32)
+	return err == nil
+}
@@ -0,0 +1,69 @@
+package whois
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseBulkLine(t *testing.T) {
+	tests := []struct {
+		name   string
+		line   string
+		want   BulkRecord
+		wantOK bool
+	}{
+		{
+			name:   "non-verbose header is filtered",
+			line:   "AS      | IP               | AS Name",
+			wantOK: false,
+		},
+		{
+			name:   "non-verbose data row",
+			line:   "15169   | 8.8.8.8          | GOOGLE, US",
+			want:   BulkRecord{ASN: "15169", IP: "8.8.8.8", ASName: "GOOGLE, US"},
+			wantOK: true,
+		},
+		{
+			name:   "non-verbose unresolved row",
+			line:   "NA      | 192.0.2.1        | NA",
+			want:   BulkRecord{ASN: "NA", IP: "192.0.2.1", ASName: "NA"},
+			wantOK: true,
+		},
+		{
+			name:   "verbose header is filtered",
+			line:   "AS | IP | BGP Prefix | CC | Registry | Allocated | AS Name",
+			wantOK: false,
+		},
+		{
+			name: "verbose data row",
+			line: "15169 | 8.8.8.8 | 8.8.8.0/24 | US | arin | 1992-12-01 | GOOGLE, US",
+			want: BulkRecord{
+				ASN:       "15169",
+				IP:        "8.8.8.8",
+				BGPPrefix: "8.8.8.0/24",
+				CC:        "US",
+				Registry:  "arin",
+				Allocated: "1992-12-01",
+				ASName:    "GOOGLE, US",
+			},
+			wantOK: true,
+		},
+		{
+			name:   "wrong field count",
+			line:   "15169 | 8.8.8.8",
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseBulkLine(tt.line)
+			if ok != tt.wantOK {
+				t.Fatalf("parseBulkLine(%q) ok = %v, want %v", tt.line, ok, tt.wantOK)
+			}
+			if ok && !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("parseBulkLine(%q) = %+v, want %+v", tt.line, got, tt.want)
+			}
+		})
+	}
+}
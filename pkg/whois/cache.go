@@ -0,0 +1,127 @@
+package whois
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultCacheMaxEntries bounds the default MemoryCache's size.
+	DefaultCacheMaxEntries = 10000
+	// DefaultCachePositiveTTL is how long a successful query is cached
+	// for by default.
+	DefaultCachePositiveTTL = time.Hour
+	// DefaultCacheNegativeTTL is how long a failed query is cached
+	// for by default. It's kept short relative to DefaultCachePositiveTTL
+	// so a transient failure doesn't get stuck in the cache, while
+	// still protecting upstream servers from being hammered by a batch
+	// containing many unresolvable queries.
+	DefaultCacheNegativeTTL = 5 * time.Minute
+)
+
+// CacheKey identifies one cached WHOIS lookup.
+type CacheKey struct {
+	Server string
+	Query  string
+}
+
+// Cache stores the outcome of WHOIS queries, including failures, so
+// that Client.QueryServer can avoid repeating a lookup it has already
+// performed. Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the cached outcome for key, if any entry for it is
+	// present and not expired.
+	Get(key CacheKey) (resp *Response, err error, ok bool)
+	// Set records the outcome of looking up key. Implementations
+	// typically apply a shorter TTL when err != nil.
+	Set(key CacheKey, resp *Response, err error)
+}
+
+// MemoryCache is an in-memory Cache with LRU eviction once MaxEntries
+// is reached, and separate TTLs for successful and failed lookups.
+type MemoryCache struct {
+	MaxEntries  int
+	PositiveTTL time.Duration
+	NegativeTTL time.Duration
+
+	mu    sync.Mutex
+	ll    *list.List
+	items map[CacheKey]*list.Element
+}
+
+type memoryCacheEntry struct {
+	key       CacheKey
+	resp      *Response
+	err       error
+	expiresAt time.Time
+}
+
+// NewMemoryCache builds a MemoryCache evicting least-recently-used
+// entries once it holds maxEntries, with positiveTTL and negativeTTL
+// governing how long successful and failed lookups are kept.
+func NewMemoryCache(maxEntries int, positiveTTL, negativeTTL time.Duration) *MemoryCache {
+	return &MemoryCache{
+		MaxEntries:  maxEntries,
+		PositiveTTL: positiveTTL,
+		NegativeTTL: negativeTTL,
+		ll:          list.New(),
+		items:       make(map[CacheKey]*list.Element),
+	}
+}
+
+// Get implements Cache.
+func (c *MemoryCache) Get(key CacheKey) (*Response, error, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return nil, nil, false
+	}
+
+	entry := elem.Value.(*memoryCacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.removeElement(elem)
+		return nil, nil, false
+	}
+
+	c.ll.MoveToFront(elem)
+	return entry.resp, entry.err, true
+}
+
+// Set implements Cache.
+func (c *MemoryCache) Set(key CacheKey, resp *Response, err error) {
+	ttl := c.PositiveTTL
+	if err != nil {
+		ttl = c.NegativeTTL
+	}
+	if ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &memoryCacheEntry{key: key, resp: resp, err: err, expiresAt: time.Now().Add(ttl)}
+
+	if elem, ok := c.items[key]; ok {
+		elem.Value = entry
+		c.ll.MoveToFront(elem)
+		return
+	}
+
+	elem := c.ll.PushFront(entry)
+	c.items[key] = elem
+
+	if c.MaxEntries > 0 && c.ll.Len() > c.MaxEntries {
+		c.removeElement(c.ll.Back())
+	}
+}
+
+// removeElement evicts elem. Callers must hold c.mu.
+func (c *MemoryCache) removeElement(elem *list.Element) {
+	c.ll.Remove(elem)
+	entry := elem.Value.(*memoryCacheEntry)
+	delete(c.items, entry.key)
+}
@@ -0,0 +1,91 @@
+package whois
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMemoryCacheGetSet(t *testing.T) {
+	cache := NewMemoryCache(10, time.Minute, time.Minute)
+	key := CacheKey{Server: "whois.example.com", Query: "example.com"}
+	want := &Response{Query: "example.com", Server: "whois.example.com", Raw: "data"}
+
+	cache.Set(key, want, nil)
+
+	got, err, ok := cache.Get(key)
+	if !ok {
+		t.Fatal("Get() ok = false, want true")
+	}
+	if err != nil {
+		t.Errorf("Get() err = %v, want nil", err)
+	}
+	if got != want {
+		t.Errorf("Get() = %v, want %v", got, want)
+	}
+}
+
+func TestMemoryCacheNegativeCaching(t *testing.T) {
+	cache := NewMemoryCache(10, time.Minute, time.Minute)
+	key := CacheKey{Server: "whois.example.com", Query: "example.com"}
+	wantErr := errors.New("connection refused")
+
+	cache.Set(key, nil, wantErr)
+
+	_, err, ok := cache.Get(key)
+	if !ok {
+		t.Fatal("Get() ok = false, want true")
+	}
+	if !errors.Is(err, wantErr) {
+		t.Errorf("Get() err = %v, want %v", err, wantErr)
+	}
+}
+
+func TestMemoryCacheExpiry(t *testing.T) {
+	cache := NewMemoryCache(10, time.Millisecond, time.Millisecond)
+	key := CacheKey{Server: "whois.example.com", Query: "example.com"}
+	cache.Set(key, &Response{Raw: "data"}, nil)
+
+	time.Sleep(10 * time.Millisecond)
+
+	if _, _, ok := cache.Get(key); ok {
+		t.Error("Get() ok = true after TTL expired, want false")
+	}
+}
+
+func TestMemoryCacheLRUEviction(t *testing.T) {
+	cache := NewMemoryCache(2, time.Minute, time.Minute)
+
+	keyA := CacheKey{Server: "s", Query: "a"}
+	keyB := CacheKey{Server: "s", Query: "b"}
+	keyC := CacheKey{Server: "s", Query: "c"}
+
+	cache.Set(keyA, &Response{Raw: "a"}, nil)
+	cache.Set(keyB, &Response{Raw: "b"}, nil)
+
+	// Touch A so B becomes the least recently used entry.
+	cache.Get(keyA)
+
+	cache.Set(keyC, &Response{Raw: "c"}, nil)
+
+	if _, _, ok := cache.Get(keyB); ok {
+		t.Error("keyB should have been evicted, but is still present")
+	}
+	if _, _, ok := cache.Get(keyA); !ok {
+		t.Error("keyA should still be present")
+	}
+	if _, _, ok := cache.Get(keyC); !ok {
+		t.Error("keyC should still be present")
+	}
+}
+
+func TestMemoryCacheDisabledTTL(t *testing.T) {
+	cache := NewMemoryCache(10, 0, time.Minute)
+	key := CacheKey{Server: "s", Query: "a"}
+
+	cache.Set(key, &Response{Raw: "a"}, nil) // PositiveTTL is 0: not cached
+
+	if _, _, ok := cache.Get(key); ok {
+		t.Error("Get() ok = true, want false since PositiveTTL is 0")
+	}
+}
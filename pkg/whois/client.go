@@ -0,0 +1,192 @@
+// Package whois implements a small client for the legacy port-43 WHOIS
+// protocol, suitable for embedding in other Go programs.
+package whois
+
+import (
+	"context"
+	"net"
+	"time"
+)
+
+const (
+	// DefaultServer is used as the starting point for queries when no
+	// server is explicitly configured. It is also the server IANA
+	// delegates referrals from.
+	DefaultServer = "whois.iana.org"
+	// DefaultPort is the standard WHOIS protocol port.
+	DefaultPort = 43
+	// DefaultTimeout bounds how long a single query (including any
+	// redirects it follows) may take.
+	DefaultTimeout = 15 * time.Second
+	// DefaultConcurrency is how many queries QueryBatch runs at once
+	// when Concurrency is left unset.
+	DefaultConcurrency = 10
+	// DefaultMaxAttempts is how many times queryOnce is tried, in
+	// total, before giving up on a connection error or a rate-limited
+	// response.
+	DefaultMaxAttempts = 3
+	// DefaultRetryBaseDelay is the base of the exponential backoff
+	// between retries: attempt N waits DefaultRetryBaseDelay * 2^N.
+	DefaultRetryBaseDelay = 250 * time.Millisecond
+
+	// maxBufferSize caps how much of a single server's response we will
+	// buffer, to protect against a misbehaving or malicious server.
+	maxBufferSize = 32 * 1024
+)
+
+// Dialer abstracts the creation of the TCP connection used to speak to a
+// WHOIS server. Callers can supply their own implementation to route
+// queries through a SOCKS5 proxy (golang.org/x/net/proxy), attach a
+// custom TLS or net.Dialer.Control hook, or stub out the network in
+// tests. The zero value of *net.Dialer already satisfies this interface.
+type Dialer interface {
+	DialContext(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+// Client is a WHOIS client. The zero value is not usable; construct one
+// with NewClient.
+type Client struct {
+	// Server is the server queries start at when Query is called
+	// instead of QueryServer, if AutoRoute is off or ServerFor fails.
+	Server string
+	// Port is the TCP port used for every query.
+	Port int
+	// Timeout bounds how long a query, including any redirects it
+	// follows, may run. Zero means no timeout beyond ctx.
+	Timeout time.Duration
+	// ShowRedirects makes Query report every server visited while
+	// following a referral, instead of only the final response.
+	ShowRedirects bool
+	// Dialer is used to establish the TCP connection to each WHOIS
+	// server. Defaults to &net.Dialer{}.
+	Dialer Dialer
+	// Verbose requests the extended response format from servers that
+	// support it, such as the "verbose" line in Team Cymru's bulk
+	// WHOIS protocol.
+	Verbose bool
+	// Cache, if set, is consulted before each query and updated after
+	// it, keyed by the (server, query) pair actually dialed.
+	Cache Cache
+	// AutoRoute makes Query resolve the starting server for each query
+	// via ServerFor instead of always starting at Server. Defaults to
+	// true; set WithAutoRoute(false) to pin every query to Server.
+	AutoRoute bool
+	// Mode selects between WHOIS and RDAP. Defaults to ModeWHOIS; has
+	// no effect unless RDAP is also set.
+	Mode Mode
+	// RDAP, if set, is used by Query for ModeRDAP and ModeAuto.
+	RDAP RDAPQuerier
+	// Concurrency is how many queries QueryBatch runs at once.
+	Concurrency int
+	// RateLimiter, if set, is consulted before dialing each server, to
+	// keep a batch of queries from tripping that server's abuse
+	// protection. Defaults to NewRateLimiter()'s conservative
+	// per-registry defaults; set to nil to disable.
+	RateLimiter *RateLimiter
+	// MaxAttempts is how many times a query is tried, in total, before
+	// giving up on a connection error or a rate-limited response.
+	MaxAttempts int
+	// RetryBaseDelay is the base of the exponential backoff between
+	// attempts: attempt N waits RetryBaseDelay * 2^N.
+	RetryBaseDelay time.Duration
+}
+
+// Option configures a Client constructed by NewClient.
+type Option func(*Client)
+
+// WithServer sets the server a Client starts queries at when
+// AutoRoute is off or ServerFor fails.
+func WithServer(server string) Option {
+	return func(c *Client) { c.Server = server }
+}
+
+// WithPort sets the TCP port a Client connects on.
+func WithPort(port int) Option {
+	return func(c *Client) { c.Port = port }
+}
+
+// WithTimeout bounds how long a query, including any redirects it
+// follows, may run.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) { c.Timeout = timeout }
+}
+
+// WithShowRedirects makes the Client report every server visited while
+// following a referral.
+func WithShowRedirects(show bool) Option {
+	return func(c *Client) { c.ShowRedirects = show }
+}
+
+// WithDialer overrides the Dialer used to establish connections to
+// WHOIS servers, e.g. to route through a SOCKS5 proxy.
+func WithDialer(d Dialer) Option {
+	return func(c *Client) { c.Dialer = d }
+}
+
+// WithVerbose requests the extended response format from servers that
+// support it, such as Team Cymru's bulk WHOIS protocol.
+func WithVerbose(verbose bool) Option {
+	return func(c *Client) { c.Verbose = verbose }
+}
+
+// WithCache makes the Client consult cache before performing a query
+// and record the outcome in it afterwards, keyed by (server, query).
+func WithCache(cache Cache) Option {
+	return func(c *Client) { c.Cache = cache }
+}
+
+// WithAutoRoute controls whether Query resolves each query's starting
+// server via ServerFor (the default) or always starts at Server.
+func WithAutoRoute(auto bool) Option {
+	return func(c *Client) { c.AutoRoute = auto }
+}
+
+// WithMode selects between WHOIS and RDAP. Has no effect unless
+// WithRDAP is also used.
+func WithMode(mode Mode) Option {
+	return func(c *Client) { c.Mode = mode }
+}
+
+// WithRDAP supplies the RDAP client used for ModeRDAP and ModeAuto.
+func WithRDAP(rdap RDAPQuerier) Option {
+	return func(c *Client) { c.RDAP = rdap }
+}
+
+// WithConcurrency sets how many queries QueryBatch runs at once.
+func WithConcurrency(n int) Option {
+	return func(c *Client) { c.Concurrency = n }
+}
+
+// WithRateLimit caps queries to server at rps requests per second,
+// with up to burst queries allowed through as an initial burst. It
+// overrides any default limit for that server.
+func WithRateLimit(server string, rps float64, burst int) Option {
+	return func(c *Client) {
+		if c.RateLimiter == nil {
+			c.RateLimiter = NewRateLimiter()
+		}
+		c.RateLimiter.Set(server, rps, burst)
+	}
+}
+
+// NewClient builds a Client with sensible defaults, applying opts in
+// order.
+func NewClient(opts ...Option) *Client {
+	c := &Client{
+		Server:         DefaultServer,
+		Port:           DefaultPort,
+		Timeout:        DefaultTimeout,
+		Dialer:         &net.Dialer{},
+		AutoRoute:      true,
+		Concurrency:    DefaultConcurrency,
+		RateLimiter:    NewRateLimiter(),
+		MaxAttempts:    DefaultMaxAttempts,
+		RetryBaseDelay: DefaultRetryBaseDelay,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
@@ -0,0 +1,27 @@
+package whois
+
+import "context"
+
+// Mode selects which protocol Client.Query uses.
+type Mode int
+
+const (
+	// ModeWHOIS always uses the legacy port-43 WHOIS protocol. This is
+	// the default, so existing callers are unaffected unless they opt
+	// in to RDAP.
+	ModeWHOIS Mode = iota
+	// ModeRDAP always uses RDAP and never falls back to WHOIS.
+	ModeRDAP
+	// ModeAuto tries RDAP first and falls back to WHOIS if the RDAP
+	// query fails, e.g. because the registry hasn't stood up an RDAP
+	// server yet, or ErrNotFound on a server that has.
+	ModeAuto
+)
+
+// RDAPQuerier looks up a query over RDAP, returning a parsed Info.
+// *rdap.Client (package pkg/rdap) satisfies this interface; it's
+// defined here rather than imported to keep pkg/whois free of a
+// dependency on pkg/rdap.
+type RDAPQuerier interface {
+	Query(ctx context.Context, query string) (*Info, error)
+}
@@ -0,0 +1,230 @@
+package whois
+
+import (
+	"math/big"
+	"net"
+	"strings"
+)
+
+// Info is a structured view of a WHOIS response, covering both the
+// domain-registration fields returned by registrars/registries and the
+// network fields returned by the regional internet registries (RIRs)
+// for IP and ASN lookups.
+type Info struct {
+	// Domain fields.
+	Domain      string
+	Registrar   string
+	CreatedDate string
+	UpdatedDate string
+	ExpiresDate string
+	NameServers []string
+	Status      []string
+
+	// IP/ASN (RIR) fields.
+	NetName     string
+	OrgName     string
+	Country     string
+	CIDR        string
+	ASN         string
+	Description []string
+
+	// Fields holds every key/value pair seen in the response, keyed by
+	// lowercase field name, for callers that need something Info
+	// doesn't surface directly. For the range keys (inetnum, route,
+	// cidr, ...) only the most specific block's values are kept.
+	Fields map[string][]string
+}
+
+// domain-record key aliases, by normalized (lowercase, trimmed) key.
+var (
+	domainKeys    = map[string]bool{"domain name": true, "domain": true}
+	registrarKeys = map[string]bool{"registrar": true, "sponsoring registrar": true}
+	createdKeys   = map[string]bool{"creation date": true, "created": true, "created on": true}
+	updatedKeys   = map[string]bool{"updated date": true, "last-modified": true, "modified": true}
+	expiresKeys   = map[string]bool{"registry expiry date": true, "expiration date": true, "expiry date": true, "paid-till": true}
+	nserverKeys   = map[string]bool{"name server": true, "nserver": true}
+	statusKeys    = map[string]bool{"domain status": true, "status": true}
+	netnameKeys   = map[string]bool{"netname": true}
+	orgnameKeys   = map[string]bool{"orgname": true, "org-name": true, "organization": true, "org": true}
+	countryKeys   = map[string]bool{"country": true}
+	cidrKeys      = map[string]bool{"cidr": true, "inetnum": true, "inet6num": true, "route": true, "route6": true}
+	asnKeys       = map[string]bool{"origin": true, "originas": true, "aut-num": true}
+	descrKeys     = map[string]bool{"descr": true}
+)
+
+// Parse turns the plaintext response from a WHOIS server into a
+// structured Info. It tolerates both the "Name: value"-per-line format
+// used by registrars/registries and the RPSL-ish format used by the
+// RIRs, skips "#"/"%" comment lines, and merges repeated keys (such as
+// "nserver" or "descr") into slices.
+//
+// RIR responses for a single query often stack several inetnum/route
+// objects, from least to most specific (e.g. a /8 allocation followed
+// by the /24 actually assigned). Parse keeps only the most specific
+// one for CIDR, since that's almost always what callers want.
+func Parse(raw string) *Info {
+	info := &Info{Fields: make(map[string][]string)}
+
+	var bestBlock map[string][]string
+	var bestSize *big.Int
+
+	for _, block := range splitBlocks(raw) {
+		fields := parseBlock(block)
+		if len(fields) == 0 {
+			continue
+		}
+
+		for key, values := range fields {
+			if cidrKeys[key] {
+				continue // merged separately below, using specificity
+			}
+			info.Fields[key] = append(info.Fields[key], values...)
+		}
+
+		for key, values := range fields {
+			if !cidrKeys[key] {
+				continue
+			}
+			for _, value := range values {
+				size := rangeSize(value)
+				if size == nil {
+					continue
+				}
+				if bestSize == nil || size.Cmp(bestSize) < 0 {
+					bestSize = size
+					bestBlock = fields
+				}
+			}
+		}
+	}
+
+	if bestBlock != nil {
+		for key, values := range bestBlock {
+			if cidrKeys[key] {
+				info.Fields[key] = append(info.Fields[key], values...)
+			}
+		}
+	}
+
+	populateInfo(info)
+
+	return info
+}
+
+// populateInfo fills in Info's typed fields from info.Fields.
+func populateInfo(info *Info) {
+	for key, values := range info.Fields {
+		if len(values) == 0 {
+			continue
+		}
+		switch {
+		case domainKeys[key]:
+			info.Domain = values[0]
+		case registrarKeys[key]:
+			info.Registrar = values[0]
+		case createdKeys[key]:
+			info.CreatedDate = values[0]
+		case updatedKeys[key]:
+			info.UpdatedDate = values[0]
+		case expiresKeys[key]:
+			info.ExpiresDate = values[0]
+		case nserverKeys[key]:
+			info.NameServers = append(info.NameServers, values...)
+		case statusKeys[key]:
+			info.Status = append(info.Status, values...)
+		case netnameKeys[key]:
+			info.NetName = values[0]
+		case orgnameKeys[key]:
+			info.OrgName = values[0]
+		case countryKeys[key]:
+			info.Country = values[0]
+		case cidrKeys[key]:
+			info.CIDR = values[0]
+		case asnKeys[key]:
+			info.ASN = values[0]
+		case descrKeys[key]:
+			info.Description = append(info.Description, values...)
+		}
+	}
+}
+
+// splitBlocks splits a raw response on blank lines, since RIR
+// responses stack multiple independent objects (e.g. several inetnum
+// blocks) separated that way.
+func splitBlocks(raw string) []string {
+	var blocks []string
+	var current []string
+
+	for _, line := range strings.Split(raw, "\n") {
+		if strings.TrimSpace(line) == "" {
+			if len(current) > 0 {
+				blocks = append(blocks, strings.Join(current, "\n"))
+				current = nil
+			}
+			continue
+		}
+		current = append(current, line)
+	}
+	if len(current) > 0 {
+		blocks = append(blocks, strings.Join(current, "\n"))
+	}
+
+	return blocks
+}
+
+// parseBlock parses one block of "key: value" lines into a map of
+// normalized key to the values seen for it, in order, skipping "#"/"%"
+// comment lines.
+func parseBlock(block string) map[string][]string {
+	fields := make(map[string][]string)
+
+	for _, line := range strings.Split(block, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "%") {
+			continue
+		}
+
+		idx := strings.Index(trimmed, ":")
+		if idx < 0 {
+			continue
+		}
+
+		key := strings.ToLower(strings.TrimSpace(trimmed[:idx]))
+		value := strings.TrimSpace(trimmed[idx+1:])
+		if key == "" || value == "" {
+			continue
+		}
+
+		fields[key] = append(fields[key], value)
+	}
+
+	return fields
+}
+
+// rangeSize returns the number of addresses covered by value, which
+// may be a CIDR ("192.0.2.0/24") or an ARIN-style range
+// ("192.0.2.0 - 192.0.2.255"). It returns nil if value is neither.
+func rangeSize(value string) *big.Int {
+	if _, network, err := net.ParseCIDR(value); err == nil {
+		ones, bits := network.Mask.Size()
+		return new(big.Int).Lsh(big.NewInt(1), uint(bits-ones))
+	}
+
+	if from, to, ok := strings.Cut(value, "-"); ok {
+		start := net.ParseIP(strings.TrimSpace(from))
+		end := net.ParseIP(strings.TrimSpace(to))
+		if start == nil || end == nil {
+			return nil
+		}
+		size := new(big.Int).Sub(ipToInt(end), ipToInt(start))
+		return size.Add(size, big.NewInt(1))
+	}
+
+	return nil
+}
+
+// ipToInt represents ip (v4 or v6) as an unsigned integer so ranges
+// can be compared and subtracted.
+func ipToInt(ip net.IP) *big.Int {
+	return new(big.Int).SetBytes(ip.To16())
+}
@@ -0,0 +1,97 @@
+package whois
+
+import "testing"
+
+func TestParseDomainRecord(t *testing.T) {
+	raw := `Domain Name: EXAMPLE.COM
+Registrar: Example Registrar, Inc.
+Creation Date: 1995-08-14T04:00:00Z
+Updated Date: 2024-08-14T07:01:35Z
+Registry Expiry Date: 2025-08-13T04:00:00Z
+Name Server: A.IANA-SERVERS.NET
+Name Server: B.IANA-SERVERS.NET
+Domain Status: clientTransferProhibited
+`
+	info := Parse(raw)
+
+	if info.Domain != "EXAMPLE.COM" {
+		t.Errorf("Domain = %q, want %q", info.Domain, "EXAMPLE.COM")
+	}
+	if info.Registrar != "Example Registrar, Inc." {
+		t.Errorf("Registrar = %q", info.Registrar)
+	}
+	if info.CreatedDate != "1995-08-14T04:00:00Z" {
+		t.Errorf("CreatedDate = %q", info.CreatedDate)
+	}
+	if len(info.NameServers) != 2 {
+		t.Errorf("NameServers = %v, want 2 entries", info.NameServers)
+	}
+	if len(info.Status) != 1 {
+		t.Errorf("Status = %v, want 1 entry", info.Status)
+	}
+}
+
+func TestParseSkipsComments(t *testing.T) {
+	raw := `% This is a RIPE comment
+# This is an ARIN comment
+netname: EXAMPLE-NET
+`
+	info := Parse(raw)
+	if info.NetName != "EXAMPLE-NET" {
+		t.Errorf("NetName = %q, want %q", info.NetName, "EXAMPLE-NET")
+	}
+	if len(info.Fields) != 1 {
+		t.Errorf("Fields = %v, want only netname", info.Fields)
+	}
+}
+
+func TestParsePicksMostSpecificInetnum(t *testing.T) {
+	raw := `inetnum:        192.0.0.0 - 192.0.255.255
+netname:        BIG-BLOCK
+country:        US
+
+inetnum:        192.0.2.0 - 192.0.2.255
+netname:        SMALL-BLOCK
+country:        US
+`
+	info := Parse(raw)
+
+	if info.CIDR != "192.0.2.0 - 192.0.2.255" {
+		t.Errorf("CIDR = %q, want the more specific /24 range", info.CIDR)
+	}
+}
+
+func TestParseMostSpecificRouteCIDR(t *testing.T) {
+	raw := `route:   192.0.2.0/24
+descr:   big
+
+route:   192.0.2.128/25
+descr:   small
+`
+	info := Parse(raw)
+
+	if info.CIDR != "192.0.2.128/25" {
+		t.Errorf("CIDR = %q, want the /25", info.CIDR)
+	}
+	if len(info.Description) != 2 {
+		t.Errorf("Description = %v, want both descr values merged", info.Description)
+	}
+}
+
+func TestRangeSize(t *testing.T) {
+	tests := []struct {
+		value   string
+		wantNil bool
+	}{
+		{"192.0.2.0/24", false},
+		{"192.0.2.0 - 192.0.2.255", false},
+		{"not-a-range", true},
+	}
+
+	for _, tt := range tests {
+		got := rangeSize(tt.value)
+		if (got == nil) != tt.wantNil {
+			t.Errorf("rangeSize(%q) = %v, wantNil %v", tt.value, got, tt.wantNil)
+		}
+	}
+}
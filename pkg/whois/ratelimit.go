@@ -0,0 +1,145 @@
+package whois
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// serverLimit is the default token-bucket configuration for a
+// well-known registry, chosen conservatively to avoid tripping its
+// abuse protection when querying many items in a batch.
+type serverLimit struct {
+	rps   float64
+	burst int
+}
+
+// defaultServerLimits covers the registries most likely to be queried
+// in bulk. Anything not listed here is unrestricted unless added with
+// WithRateLimit.
+var defaultServerLimits = map[string]serverLimit{
+	"whois.verisign-grs.com": {rps: 1, burst: 2},
+	"whois.pir.org":          {rps: 2, burst: 4},
+	"whois.nic.uk":           {rps: 2, burst: 4},
+	"whois.arin.net":         {rps: 5, burst: 10},
+	"whois.ripe.net":         {rps: 5, burst: 10},
+	"whois.apnic.net":        {rps: 5, burst: 10},
+	"whois.lacnic.net":       {rps: 5, burst: 10},
+	"whois.afrinic.net":      {rps: 5, burst: 10},
+	"whois.iana.org":         {rps: 2, burst: 4},
+	CymruBulkServer:          {rps: 1, burst: 1},
+}
+
+// RateLimiter enforces a per-server token-bucket rate limit, so a
+// batch of queries against one registry doesn't get the caller banned
+// from it. The zero value has no limits configured; use
+// NewRateLimiter for one preloaded with sensible defaults.
+type RateLimiter struct {
+	mu      sync.Mutex
+	limits  map[string]serverLimit
+	buckets map[string]*tokenBucket
+}
+
+// NewRateLimiter builds a RateLimiter preloaded with conservative
+// defaults for well-known registries.
+func NewRateLimiter() *RateLimiter {
+	limits := make(map[string]serverLimit, len(defaultServerLimits))
+	for server, limit := range defaultServerLimits {
+		limits[server] = limit
+	}
+
+	return &RateLimiter{
+		limits:  limits,
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+// Set configures the rate limit for server, overriding any default.
+func (r *RateLimiter) Set(server string, rps float64, burst int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.limits == nil {
+		r.limits = make(map[string]serverLimit)
+		r.buckets = make(map[string]*tokenBucket)
+	}
+	r.limits[server] = serverLimit{rps: rps, burst: burst}
+	delete(r.buckets, server) // rebuilt lazily with the new limit
+}
+
+// Wait blocks until a token for server is available, or ctx is done.
+// Servers with no configured limit return immediately.
+func (r *RateLimiter) Wait(ctx context.Context, server string) error {
+	bucket := r.bucketFor(server)
+	if bucket == nil {
+		return nil
+	}
+	return bucket.wait(ctx)
+}
+
+func (r *RateLimiter) bucketFor(server string) *tokenBucket {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if bucket, ok := r.buckets[server]; ok {
+		return bucket
+	}
+
+	limit, ok := r.limits[server]
+	if !ok {
+		return nil
+	}
+
+	bucket := newTokenBucket(limit.rps, limit.burst)
+	r.buckets[server] = bucket
+	return bucket
+}
+
+// tokenBucket is a simple token-bucket rate limiter.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rps float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:   rps,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// wait blocks until a token is available, or ctx is done.
+func (b *tokenBucket) wait(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+		b.last = now
+
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return nil
+		}
+
+		delay := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		b.mu.Unlock()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package whois
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTokenBucketAllowsBurst(t *testing.T) {
+	bucket := newTokenBucket(1, 3)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		start := time.Now()
+		if err := bucket.wait(ctx); err != nil {
+			t.Fatalf("wait() error = %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 10*time.Millisecond {
+			t.Errorf("wait() call %d took %v, want near-instant (within burst)", i, elapsed)
+		}
+	}
+}
+
+func TestTokenBucketThrottlesBeyondBurst(t *testing.T) {
+	bucket := newTokenBucket(100, 1) // 1 token/10ms after the burst
+	ctx := context.Background()
+
+	if err := bucket.wait(ctx); err != nil {
+		t.Fatalf("wait() error = %v", err)
+	}
+
+	start := time.Now()
+	if err := bucket.wait(ctx); err != nil {
+		t.Fatalf("wait() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 5*time.Millisecond {
+		t.Errorf("wait() returned after %v, want it to block for a refill", elapsed)
+	}
+}
+
+func TestTokenBucketRespectsContextCancellation(t *testing.T) {
+	bucket := newTokenBucket(0.1, 1) // effectively never refills within the test
+	ctx := context.Background()
+	if err := bucket.wait(ctx); err != nil {
+		t.Fatalf("wait() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := bucket.wait(ctx); err == nil {
+		t.Error("wait() error = nil, want context deadline exceeded")
+	}
+}
+
+func TestRateLimiterUnconfiguredServerDoesNotBlock(t *testing.T) {
+	limiter := &RateLimiter{limits: map[string]serverLimit{}, buckets: map[string]*tokenBucket{}}
+
+	start := time.Now()
+	if err := limiter.Wait(context.Background(), "unknown.example"); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Millisecond {
+		t.Errorf("Wait() took %v for an unconfigured server, want near-instant", elapsed)
+	}
+}
+
+func TestRateLimiterSetOverridesDefault(t *testing.T) {
+	limiter := NewRateLimiter()
+	limiter.Set("whois.verisign-grs.com", 1000, 1000)
+
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		if err := limiter.Wait(context.Background(), "whois.verisign-grs.com"); err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("Wait() took %v for an overridden high-burst limit, want near-instant", elapsed)
+	}
+}
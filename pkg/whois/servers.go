@@ -0,0 +1,223 @@
+package whois
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Well-known registry servers used outside the TLD table, for IP and
+// ASN queries.
+const (
+	ArinServer    = "whois.arin.net"
+	RIPEServer    = "whois.ripe.net"
+	APNICServer   = "whois.apnic.net"
+	LACNICServer  = "whois.lacnic.net"
+	AFRINICServer = "whois.afrinic.net"
+)
+
+// tldServers maps a TLD (without the leading dot) to its authoritative
+// WHOIS server. It covers the most commonly queried TLDs; anything
+// missing is resolved on demand via the IANA bootstrap and cached, see
+// (*Client).ServerFor.
+var tldServers = map[string]string{
+	"com":  "whois.verisign-grs.com",
+	"net":  "whois.verisign-grs.com",
+	"org":  "whois.pir.org",
+	"info": "whois.afilias.net",
+	"biz":  "whois.biz",
+	"io":   "whois.nic.io",
+	"co":   "whois.nic.co",
+	"dev":  "whois.nic.google",
+	"app":  "whois.nic.google",
+	"uk":   "whois.nic.uk",
+	"de":   "whois.denic.de",
+	"nl":   "whois.domain-registry.nl",
+	"fr":   "whois.nic.fr",
+	"eu":   "whois.eu",
+	"us":   "whois.nic.us",
+	"ca":   "whois.cira.ca",
+	"au":   "whois.auda.org.au",
+	"jp":   "whois.jprs.jp",
+	"cn":   "whois.cnnic.cn",
+	"ru":   "whois.tcinet.ru",
+	"xyz":  "whois.nic.xyz",
+	"me":   "whois.nic.me",
+}
+
+// rirBlock is one of a small, non-exhaustive set of well-known IPv4
+// allocations per regional internet registry (RIR), used as a
+// fast-path so common IP lookups skip the ARIN referral round trip.
+// Anything not covered here still resolves correctly: it's queried
+// against ARIN, whose "ReferralServer:" line is followed like any
+// other redirect.
+type rirBlock struct {
+	cidr   string
+	server string
+}
+
+var rirBlocks = []rirBlock{
+	{"2.0.0.0/8", RIPEServer},
+	{"5.0.0.0/8", RIPEServer},
+	{"31.0.0.0/8", RIPEServer},
+	{"46.0.0.0/8", RIPEServer},
+	{"62.0.0.0/8", RIPEServer},
+	{"77.0.0.0/8", RIPEServer},
+	{"78.0.0.0/8", RIPEServer},
+	{"1.0.0.0/8", APNICServer},
+	{"14.0.0.0/8", APNICServer},
+	{"27.0.0.0/8", APNICServer},
+	{"36.0.0.0/8", APNICServer},
+	{"58.0.0.0/8", APNICServer},
+	{"101.0.0.0/8", APNICServer},
+	{"177.0.0.0/8", LACNICServer},
+	{"179.0.0.0/8", LACNICServer},
+	{"181.0.0.0/8", LACNICServer},
+	{"186.0.0.0/8", LACNICServer},
+	{"187.0.0.0/8", LACNICServer},
+	{"41.0.0.0/8", AFRINICServer},
+	{"102.0.0.0/8", AFRINICServer},
+	{"105.0.0.0/8", AFRINICServer},
+	{"154.0.0.0/8", AFRINICServer},
+	{"196.0.0.0/8", AFRINICServer},
+}
+
+var parsedRIRBlocks = parseRIRBlocks(rirBlocks)
+
+func parseRIRBlocks(blocks []rirBlock) []struct {
+	network *net.IPNet
+	server  string
+} {
+	parsed := make([]struct {
+		network *net.IPNet
+		server  string
+	}, 0, len(blocks))
+
+	for _, b := range blocks {
+		_, network, err := net.ParseCIDR(b.cidr)
+		if err != nil {
+			continue
+		}
+		parsed = append(parsed, struct {
+			network *net.IPNet
+			server  string
+		}{network, b.server})
+	}
+
+	return parsed
+}
+
+// ServerFor returns the WHOIS server authoritative for query, which
+// may be a domain name, an IPv4/IPv6 address, or an ASN ("AS<n>" or a
+// bare number). Domain lookups consult the embedded tldServers table
+// first; if the TLD isn't in it, ServerFor queries whois.iana.org for
+// the TLD's "refer:" server and caches the result, shared across all
+// Clients, for future calls.
+func (c *Client) ServerFor(ctx context.Context, query string) (string, error) {
+	query = strings.TrimSpace(query)
+
+	if ip := net.ParseIP(query); ip != nil {
+		return rirServerFor(ip), nil
+	}
+
+	if isASNQuery(query) {
+		return ArinServer, nil
+	}
+
+	tld := tldOf(query)
+
+	if server, ok := tldServers[tld]; ok {
+		return server, nil
+	}
+
+	if server, ok := c.cachedTLDServer(tld); ok {
+		return server, nil
+	}
+
+	server, err := c.bootstrapTLDServer(ctx, tld)
+	if err != nil {
+		return "", err
+	}
+
+	c.cacheTLDServer(tld, server)
+	return server, nil
+}
+
+// rirServerFor returns the best-guess RIR server for ip, defaulting to
+// ARIN (which will refer callers elsewhere for ranges it doesn't
+// manage) when ip isn't in one of the well-known blocks above.
+func rirServerFor(ip net.IP) string {
+	for _, b := range parsedRIRBlocks {
+		if b.network.Contains(ip) {
+			return b.server
+		}
+	}
+	return ArinServer
+}
+
+// isASNQuery reports whether query names an ASN, either as "AS<n>" or
+// a bare number.
+func isASNQuery(query string) bool {
+	trimmed := strings.TrimPrefix(strings.ToUpper(query), "AS")
+	_, err := strconv.ParseUint(trimmed, 10, 32)
+	return err == nil && trimmed != ""
+}
+
+// tldOf returns the lowercase TLD (the label after the last dot) of a
+// domain query, with any trailing dot stripped first.
+func tldOf(query string) string {
+	query = strings.TrimSuffix(query, ".")
+	idx := strings.LastIndex(query, ".")
+	if idx < 0 {
+		return strings.ToLower(query)
+	}
+	return strings.ToLower(query[idx+1:])
+}
+
+// bootstrapTLDServer asks whois.iana.org which server is authoritative
+// for tld, by parsing the "refer:" line out of its response.
+func (c *Client) bootstrapTLDServer(ctx context.Context, tld string) (string, error) {
+	resp, err := c.queryServerUncached(ctx, DefaultServer, tld)
+	if err != nil {
+		return "", fmt.Errorf("failed to bootstrap WHOIS server for %q via IANA: %w", tld, err)
+	}
+
+	refer := referTarget(resp.Raw)
+	if refer == "" {
+		return "", fmt.Errorf("IANA has no referral server for %q", tld)
+	}
+
+	return refer, nil
+}
+
+// referTarget extracts the server named by a "refer:" line, as
+// returned by whois.iana.org.
+func referTarget(raw string) string {
+	for _, line := range strings.Split(raw, "\n") {
+		trimmed := strings.TrimSpace(strings.ToLower(line))
+		if strings.HasPrefix(trimmed, "refer:") {
+			return strings.TrimSpace(line[strings.Index(line, ":")+1:])
+		}
+	}
+	return ""
+}
+
+// tldServerCache caches TLD->server mappings learned from the IANA
+// bootstrap, shared across all Clients since the mapping doesn't
+// depend on client configuration.
+var tldServerCache sync.Map // map[string]string
+
+func (c *Client) cachedTLDServer(tld string) (string, bool) {
+	v, ok := tldServerCache.Load(tld)
+	if !ok {
+		return "", false
+	}
+	return v.(string), true
+}
+
+func (c *Client) cacheTLDServer(tld, server string) {
+	tldServerCache.Store(tld, server)
+}
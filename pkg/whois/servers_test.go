@@ -0,0 +1,91 @@
+package whois
+
+import (
+	"context"
+	"net"
+	"testing"
+)
+
+func TestTldOf(t *testing.T) {
+	tests := map[string]string{
+		"example.com":   "com",
+		"example.co.uk": "uk",
+		"example.com.":  "com",
+		"com":           "com",
+	}
+
+	for query, want := range tests {
+		if got := tldOf(query); got != want {
+			t.Errorf("tldOf(%q) = %q, want %q", query, got, want)
+		}
+	}
+}
+
+func TestIsASNQuery(t *testing.T) {
+	tests := map[string]bool{
+		"AS15169":     true,
+		"as15169":     true,
+		"As15169":     true,
+		"aS15169":     true,
+		"15169":       true,
+		"example.com": false,
+		"":            false,
+	}
+
+	for query, want := range tests {
+		if got := isASNQuery(query); got != want {
+			t.Errorf("isASNQuery(%q) = %v, want %v", query, got, want)
+		}
+	}
+}
+
+func TestRirServerForIP(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want string
+	}{
+		{"2.2.2.2", RIPEServer},
+		{"41.1.1.1", AFRINICServer},
+		{"8.8.8.8", ArinServer}, // not in any known fast-path block
+	}
+
+	for _, tt := range tests {
+		ip := net.ParseIP(tt.ip)
+		if got := rirServerFor(ip); got != tt.want {
+			t.Errorf("rirServerFor(%s) = %q, want %q", tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestServerForKnownTLD(t *testing.T) {
+	c := NewClient()
+	server, err := c.ServerFor(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("ServerFor() error = %v", err)
+	}
+	if server != "whois.verisign-grs.com" {
+		t.Errorf("ServerFor(example.com) = %q, want whois.verisign-grs.com", server)
+	}
+}
+
+func TestServerForIP(t *testing.T) {
+	c := NewClient()
+	server, err := c.ServerFor(context.Background(), "2.2.2.2")
+	if err != nil {
+		t.Fatalf("ServerFor() error = %v", err)
+	}
+	if server != RIPEServer {
+		t.Errorf("ServerFor(2.2.2.2) = %q, want %q", server, RIPEServer)
+	}
+}
+
+func TestServerForASN(t *testing.T) {
+	c := NewClient()
+	server, err := c.ServerFor(context.Background(), "AS15169")
+	if err != nil {
+		t.Fatalf("ServerFor() error = %v", err)
+	}
+	if server != ArinServer {
+		t.Errorf("ServerFor(AS15169) = %q, want %q", server, ArinServer)
+	}
+}
@@ -0,0 +1,235 @@
+package whois
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Response is the result of a WHOIS query.
+type Response struct {
+	// Query is the domain, IP, or ASN that was looked up.
+	Query string
+	// Server is the server that produced Raw/Info, i.e. the last
+	// server visited when following a redirect, or "rdap" for a
+	// response served over RDAP.
+	Server string
+	// Raw is the unparsed text of the response. Empty for a response
+	// served over RDAP, since there's no plaintext equivalent.
+	Raw string
+	// Info is set when the response was served over RDAP, which
+	// returns structured data directly rather than text Parse must be
+	// run over.
+	Info *Info
+}
+
+// Query looks up query, following any redirects the response
+// contains. If c.Mode is ModeRDAP or ModeAuto and c.RDAP is set, the
+// query is tried over RDAP first; ModeAuto falls back to WHOIS if
+// that fails. If c.AutoRoute is set (the default), the WHOIS starting
+// server is resolved with ServerFor instead of always using c.Server;
+// this avoids the extra IANA round trip for common TLDs and correctly
+// routes IP/ASN queries that IANA doesn't handle.
+func (c *Client) Query(ctx context.Context, query string) (*Response, error) {
+	if c.Mode != ModeWHOIS && c.RDAP != nil {
+		info, err := c.queryRDAP(ctx, query)
+		if err == nil {
+			return &Response{Query: query, Server: "rdap", Info: info}, nil
+		}
+		if c.Mode == ModeRDAP {
+			return nil, err
+		}
+		// ModeAuto: fall through to WHOIS.
+	}
+
+	server := c.Server
+
+	if c.AutoRoute {
+		if resolved, err := c.ServerFor(ctx, query); err == nil {
+			server = resolved
+		}
+	}
+
+	return c.QueryServer(ctx, server, query)
+}
+
+// queryRDAP runs an RDAP lookup bounded by c.Timeout, the same way the
+// WHOIS path is bounded in queryServerUncached. Without this, a
+// hung/slow RDAP server would block Query indefinitely instead of
+// allowing ModeAuto to fall back to WHOIS.
+func (c *Client) queryRDAP(ctx context.Context, query string) (*Info, error) {
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+	return c.RDAP.Query(ctx, query)
+}
+
+// QueryServer looks up query against server directly, following any
+// "whois server:" redirects the response contains. If a Cache is
+// configured, it's consulted before dialing and updated afterwards,
+// keyed by this (server, query) hop.
+func (c *Client) QueryServer(ctx context.Context, server, query string) (*Response, error) {
+	key := CacheKey{Server: server, Query: query}
+	if c.Cache != nil {
+		if resp, err, ok := c.Cache.Get(key); ok {
+			return resp, err
+		}
+	}
+
+	resp, err := c.queryServerUncached(ctx, server, query)
+
+	if c.Cache != nil {
+		c.Cache.Set(key, resp, err)
+	}
+
+	return resp, err
+}
+
+// queryServerUncached is QueryServer's implementation, without the
+// cache lookup/update.
+func (c *Client) queryServerUncached(ctx context.Context, server, query string) (*Response, error) {
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	raw, err := c.queryOnceWithRetry(ctx, server, query)
+	if err != nil {
+		return nil, err
+	}
+
+	if redirect := redirectTarget(raw); redirect != "" && redirect != server {
+		resp, err := c.QueryServer(ctx, redirect, query)
+		if err != nil {
+			return nil, err
+		}
+		if c.ShowRedirects {
+			// resp may be the exact pointer c.Cache just stored for
+			// (redirect, query); copy it rather than mutating that
+			// cache entry in place.
+			combined := *resp
+			combined.Raw = raw + resp.Raw
+			return &combined, nil
+		}
+		return resp, nil
+	}
+
+	return &Response{Query: query, Server: server, Raw: raw}, nil
+}
+
+// queryOnceWithRetry calls queryOnce, retrying with exponential
+// backoff (up to c.MaxAttempts total attempts) on a connection error
+// or a response that looks rate-limited. It also waits on c.RateLimiter
+// for server before each attempt, if one is configured.
+func (c *Client) queryOnceWithRetry(ctx context.Context, server, query string) (string, error) {
+	maxAttempts := c.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if c.RateLimiter != nil {
+			if err := c.RateLimiter.Wait(ctx, server); err != nil {
+				return "", err
+			}
+		}
+
+		raw, err := c.queryOnce(ctx, server, query)
+		switch {
+		case err == nil && !looksRateLimited(raw):
+			return raw, nil
+		case err == nil:
+			lastErr = fmt.Errorf("rate limit exceeded response from %s", server)
+		default:
+			lastErr = err
+		}
+
+		if attempt == maxAttempts-1 {
+			break
+		}
+
+		backoff := c.RetryBaseDelay * time.Duration(1<<uint(attempt))
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return "", ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	return "", lastErr
+}
+
+// looksRateLimited reports whether raw is a "rate limit exceeded"
+// style response body rather than an actual WHOIS record.
+func looksRateLimited(raw string) bool {
+	lower := strings.ToLower(raw)
+	return strings.Contains(lower, "rate limit exceeded") ||
+		strings.Contains(lower, "too many requests") ||
+		strings.Contains(lower, "exceeded the rate limit")
+}
+
+// queryOnce performs a single query/response round trip against server,
+// without following any redirect the response contains.
+func (c *Client) queryOnce(ctx context.Context, server, query string) (string, error) {
+	addr := net.JoinHostPort(server, strconv.Itoa(c.Port))
+	conn, err := c.Dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to WHOIS server %s: %w", server, err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return "", fmt.Errorf("failed to set connection deadline: %w", err)
+		}
+	}
+
+	if _, err := fmt.Fprintf(conn, "%s\r\n", query); err != nil {
+		return "", fmt.Errorf("failed to send WHOIS query: %w", err)
+	}
+
+	reader := bufio.NewReader(conn)
+	var sb strings.Builder
+
+	for {
+		line, err := reader.ReadString('\n')
+		sb.WriteString(line)
+		if err != nil {
+			break // EOF or read error ends the response
+		}
+		if sb.Len() >= maxBufferSize {
+			break
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return "", fmt.Errorf("WHOIS query to %s: %w", server, err)
+	}
+
+	return sb.String(), nil
+}
+
+// redirectTarget returns the server named by a "whois server:" or
+// (ARIN's) "referralserver:" line in raw, or "" if the response
+// contains neither.
+func redirectTarget(raw string) string {
+	for _, line := range strings.Split(raw, "\n") {
+		trimmed := strings.TrimSpace(strings.ToLower(line))
+		if strings.HasPrefix(trimmed, "whois server:") || strings.HasPrefix(trimmed, "referralserver:") {
+			target := strings.TrimSpace(line[strings.Index(line, ":")+1:])
+			target = strings.TrimPrefix(strings.TrimPrefix(target, "whois://"), "rdap://")
+			return target
+		}
+	}
+	return ""
+}
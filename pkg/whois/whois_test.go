@@ -0,0 +1,185 @@
+package whois
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// connWithResponse returns one side of a net.Pipe that, once anything
+// is written to it (the query line), writes text back and closes.
+func connWithResponse(text string) net.Conn {
+	server, client := net.Pipe()
+	go func() {
+		buf := make([]byte, 256)
+		server.Read(buf)
+		io.WriteString(server, text)
+		server.Close()
+	}()
+	return client
+}
+
+// mapDialer returns a fixed canned response per address, keyed
+// without the port.
+type mapDialer struct {
+	responses map[string]string
+}
+
+func (d mapDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		host = address
+	}
+	text, ok := d.responses[host]
+	if !ok {
+		return nil, errors.New("no canned response for " + host)
+	}
+	return connWithResponse(text), nil
+}
+
+// scriptedDialer returns its steps in order, one per call, erroring if
+// called more times than it has steps for.
+type scriptedDialer struct {
+	mu    sync.Mutex
+	calls int
+	steps []func() (net.Conn, error)
+}
+
+func (d *scriptedDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	d.mu.Lock()
+	i := d.calls
+	d.calls++
+	d.mu.Unlock()
+
+	if i >= len(d.steps) {
+		return nil, errors.New("scriptedDialer: no more steps")
+	}
+	return d.steps[i]()
+}
+
+func testClient(dialer Dialer) *Client {
+	return &Client{
+		Server:         "whois.a.example",
+		Port:           DefaultPort,
+		Timeout:        time.Second,
+		Dialer:         dialer,
+		AutoRoute:      false,
+		MaxAttempts:    3,
+		RetryBaseDelay: time.Millisecond,
+	}
+}
+
+func TestQueryServerFollowsRedirect(t *testing.T) {
+	dialer := mapDialer{responses: map[string]string{
+		"whois.a.example": "whois server: whois.b.example\r\n",
+		"whois.b.example": "final record\r\n",
+	}}
+	c := testClient(dialer)
+
+	resp, err := c.QueryServer(context.Background(), "whois.a.example", "example.com")
+	if err != nil {
+		t.Fatalf("QueryServer() error = %v", err)
+	}
+	if resp.Server != "whois.b.example" {
+		t.Errorf("Server = %q, want whois.b.example", resp.Server)
+	}
+	if resp.Raw != "final record\r\n" {
+		t.Errorf("Raw = %q, want only the final server's text", resp.Raw)
+	}
+}
+
+func TestQueryServerShowRedirectsCombinesText(t *testing.T) {
+	dialer := mapDialer{responses: map[string]string{
+		"whois.a.example": "whois server: whois.b.example\r\n",
+		"whois.b.example": "final record\r\n",
+	}}
+	c := testClient(dialer)
+	c.ShowRedirects = true
+
+	resp, err := c.QueryServer(context.Background(), "whois.a.example", "example.com")
+	if err != nil {
+		t.Fatalf("QueryServer() error = %v", err)
+	}
+	want := "whois server: whois.b.example\r\nfinal record\r\n"
+	if resp.Raw != want {
+		t.Errorf("Raw = %q, want %q", resp.Raw, want)
+	}
+}
+
+func TestQueryServerRetriesConnectionError(t *testing.T) {
+	dialer := &scriptedDialer{steps: []func() (net.Conn, error){
+		func() (net.Conn, error) { return nil, errors.New("connection refused") },
+		func() (net.Conn, error) { return connWithResponse("real data\r\n"), nil },
+	}}
+	c := testClient(dialer)
+
+	resp, err := c.QueryServer(context.Background(), "whois.a.example", "example.com")
+	if err != nil {
+		t.Fatalf("QueryServer() error = %v", err)
+	}
+	if resp.Raw != "real data\r\n" {
+		t.Errorf("Raw = %q, want real data", resp.Raw)
+	}
+	if dialer.calls != 2 {
+		t.Errorf("calls = %d, want 2 (one failure, one success)", dialer.calls)
+	}
+}
+
+func TestQueryServerRetriesRateLimitedResponse(t *testing.T) {
+	dialer := &scriptedDialer{steps: []func() (net.Conn, error){
+		func() (net.Conn, error) { return connWithResponse("Rate limit exceeded, try later\r\n"), nil },
+		func() (net.Conn, error) { return connWithResponse("real data\r\n"), nil },
+	}}
+	c := testClient(dialer)
+
+	resp, err := c.QueryServer(context.Background(), "whois.a.example", "example.com")
+	if err != nil {
+		t.Fatalf("QueryServer() error = %v", err)
+	}
+	if resp.Raw != "real data\r\n" {
+		t.Errorf("Raw = %q, want real data", resp.Raw)
+	}
+}
+
+func TestQueryServerGivesUpAfterMaxAttempts(t *testing.T) {
+	dialer := &scriptedDialer{steps: []func() (net.Conn, error){
+		func() (net.Conn, error) { return nil, errors.New("connection refused") },
+		func() (net.Conn, error) { return nil, errors.New("connection refused") },
+		func() (net.Conn, error) { return nil, errors.New("connection refused") },
+	}}
+	c := testClient(dialer)
+
+	_, err := c.QueryServer(context.Background(), "whois.a.example", "example.com")
+	if err == nil {
+		t.Fatal("QueryServer() error = nil, want the connection error after exhausting retries")
+	}
+	if dialer.calls != 3 {
+		t.Errorf("calls = %d, want 3 (c.MaxAttempts)", dialer.calls)
+	}
+}
+
+func TestQueryUsesCache(t *testing.T) {
+	dialer := &scriptedDialer{steps: []func() (net.Conn, error){
+		func() (net.Conn, error) { return connWithResponse("record\r\n"), nil },
+	}}
+	c := testClient(dialer)
+	c.Cache = NewMemoryCache(10, time.Minute, time.Minute)
+
+	for i := 0; i < 3; i++ {
+		resp, err := c.QueryServer(context.Background(), "whois.a.example", "example.com")
+		if err != nil {
+			t.Fatalf("QueryServer() error = %v", err)
+		}
+		if resp.Raw != "record\r\n" {
+			t.Errorf("Raw = %q", resp.Raw)
+		}
+	}
+
+	if dialer.calls != 1 {
+		t.Errorf("calls = %d, want 1 (later lookups should hit the cache)", dialer.calls)
+	}
+}